@@ -0,0 +1,95 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// BatchForwarder forwards a single sub-request of a JSON-RPC batch and returns its
+// already-marshaled JSON-RPC response body (e.g. via NormalizedResponse.MarshalJSON),
+// or the error that occurred while forwarding it. This is typically a thin wrapper
+// around the network's own Forward, which is what actually applies per-upstream
+// concurrency limits, rate limits, and cache lookups for each sub-call — ForwardBatch
+// itself only bounds how many sub-calls run concurrently and reassembles the replies.
+type BatchForwarder func(ctx context.Context, sub *NormalizedRequest) ([]byte, error)
+
+// ForwardBatch runs every sub-request of parent (a JSON-RPC batch, per Batch()) through
+// forward, at most maxConcurrency at a time, and reassembles a single JSON array reply
+// that preserves the original call ordering. A sub-call with no id (a notification) is
+// omitted from the array entirely, per the JSON-RPC 2.0 spec. A sub-call that errors
+// becomes a JSON-RPC error object in its slot instead of failing the whole batch.
+func ForwardBatch(ctx context.Context, parent *NormalizedRequest, maxConcurrency int, forward BatchForwarder) ([]byte, error) {
+	subs, ok := parent.Batch()
+	if !ok {
+		return nil, fmt.Errorf("ForwardBatch: request is not a batch")
+	}
+
+	if maxConcurrency <= 0 {
+		maxConcurrency = len(subs)
+	}
+
+	bodies := make([][]byte, len(subs))
+
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+
+	for i, sub := range subs {
+		if sub.IsNotification() {
+			continue
+		}
+
+		i, sub := i, sub
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			body, err := forward(ctx, sub)
+			if err != nil {
+				body, _ = sonic.Marshal(batchErrorObject(sub, err))
+			}
+
+			bodies[i] = body
+		}()
+	}
+
+	wg.Wait()
+
+	var out bytes.Buffer
+	out.WriteByte('[')
+	wrote := false
+	for _, body := range bodies {
+		if body == nil {
+			// Either a notification (intentionally skipped above) or a forward that
+			// returned a nil body with a nil error, which we treat the same way.
+			continue
+		}
+		if wrote {
+			out.WriteByte(',')
+		}
+		out.Write(body)
+		wrote = true
+	}
+	out.WriteByte(']')
+
+	return out.Bytes(), nil
+}
+
+// batchErrorObject builds a JSON-RPC error response for a sub-call that failed to
+// forward, keyed to its original id so it lands in the right slot of the batch reply.
+func batchErrorObject(sub *NormalizedRequest, err error) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      sub.rawId(),
+		"error": map[string]interface{}{
+			"code":    -32000,
+			"message": err.Error(),
+		},
+	}
+}
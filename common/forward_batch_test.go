@@ -0,0 +1,122 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+func TestForwardBatch_PreservesOrderAndOmitsNotifications(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","method":"m1","id":1},
+		{"jsonrpc":"2.0","method":"m2"},
+		{"jsonrpc":"2.0","method":"m3","id":3}
+	]`
+	parent := NewNormalizedRequest([]byte(body))
+
+	forward := func(ctx context.Context, sub *NormalizedRequest) ([]byte, error) {
+		m, _ := sub.Method()
+		return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":"%s"}`, sub.Id(), m)), nil
+	}
+
+	out, err := ForwardBatch(context.Background(), parent, 0, forward)
+	if err != nil {
+		t.Fatalf("ForwardBatch() error = %v", err)
+	}
+
+	var replies []map[string]interface{}
+	if err := sonic.Unmarshal(out, &replies); err != nil {
+		t.Fatalf("failed to unmarshal batch reply %s: %v", out, err)
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want 2 (notification must be omitted)", len(replies))
+	}
+	if replies[0]["result"] != "m1" || replies[1]["result"] != "m3" {
+		t.Fatalf("replies out of order: %+v", replies)
+	}
+}
+
+func TestForwardBatch_SubCallErrorBecomesErrorObject(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","method":"m1","id":1},
+		{"jsonrpc":"2.0","method":"m2","id":2}
+	]`
+	parent := NewNormalizedRequest([]byte(body))
+
+	forward := func(ctx context.Context, sub *NormalizedRequest) ([]byte, error) {
+		m, _ := sub.Method()
+		if m == "m2" {
+			return nil, fmt.Errorf("upstream exhausted")
+		}
+		return []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":"ok"}`, sub.Id())), nil
+	}
+
+	out, err := ForwardBatch(context.Background(), parent, 0, forward)
+	if err != nil {
+		t.Fatalf("ForwardBatch() error = %v", err)
+	}
+
+	var replies []map[string]interface{}
+	if err := sonic.Unmarshal(out, &replies); err != nil {
+		t.Fatalf("failed to unmarshal batch reply %s: %v", out, err)
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want 2", len(replies))
+	}
+	if _, hasResult := replies[0]["result"]; !hasResult {
+		t.Fatalf("replies[0] = %+v, want a successful result", replies[0])
+	}
+	errObj, ok := replies[1]["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("replies[1] = %+v, want a JSON-RPC error object for the failed sub-call", replies[1])
+	}
+	if errObj["message"] != "upstream exhausted" {
+		t.Fatalf("error message = %v, want \"upstream exhausted\"", errObj["message"])
+	}
+}
+
+func TestForwardBatch_RespectsMaxConcurrency(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","method":"m","id":1},
+		{"jsonrpc":"2.0","method":"m","id":2},
+		{"jsonrpc":"2.0","method":"m","id":3},
+		{"jsonrpc":"2.0","method":"m","id":4}
+	]`
+	parent := NewNormalizedRequest([]byte(body))
+
+	var current, peak int32
+	forward := func(ctx context.Context, sub *NormalizedRequest) ([]byte, error) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return []byte(`{"jsonrpc":"2.0","id":1,"result":"ok"}`), nil
+	}
+
+	if _, err := ForwardBatch(context.Background(), parent, 2, forward); err != nil {
+		t.Fatalf("ForwardBatch() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Fatalf("peak concurrency = %d, want <= 2", got)
+	}
+}
+
+func TestForwardBatch_NotABatch(t *testing.T) {
+	parent := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	if _, err := ForwardBatch(context.Background(), parent, 0, nil); err == nil {
+		t.Fatalf("ForwardBatch() error = nil, want an error for a non-batch request")
+	}
+}
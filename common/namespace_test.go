@@ -0,0 +1,23 @@
+package common
+
+import "testing"
+
+func TestNormalizedRequest_Namespace(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{"eth_getBlockByNumber", "eth"},
+		{"debug_traceTransaction", "debug"},
+		{"net_version", "net"},
+		{"noUnderscore", ""},
+		{"_leadingUnderscore", ""},
+	}
+
+	for _, tc := range tests {
+		nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"` + tc.method + `","id":1}`))
+		if got := nr.Namespace(); got != tc.want {
+			t.Errorf("Namespace() for method %q = %q, want %q", tc.method, got, tc.want)
+		}
+	}
+}
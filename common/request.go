@@ -1,10 +1,15 @@
 package common
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"math"
 	"math/rand"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/rs/zerolog"
@@ -13,6 +18,18 @@ import (
 
 type RequestDirectives struct {
 	RetryEmpty bool
+
+	// TimeoutMs overrides the project/upstream default deadline for this request
+	// when > 0 (set via the X-ERPC-Timeout-Ms header).
+	TimeoutMs int
+
+	// MaxAttempts overrides the configured retry policy's maxAttempts for this
+	// request when > 0 (set via the X-ERPC-Max-Attempts header).
+	MaxAttempts int
+
+	// RetryOn overrides the configured retry policy's retryOn matchers for this
+	// request when non-empty (set via the comma-separated X-ERPC-Retry-On header).
+	RetryOn []string
 }
 
 type NormalizedRequest struct {
@@ -26,9 +43,13 @@ type NormalizedRequest struct {
 	directives     *RequestDirectives
 	jsonRpcRequest *JsonRpcRequest
 
+	ctx context.Context
+
 	lastValidResponse *NormalizedResponse
 	lastUpstream      Upstream
 
+	batch []*NormalizedRequest
+
 	Mu sync.Mutex
 }
 
@@ -72,6 +93,53 @@ func (r *NormalizedRequest) LastValidResponse() *NormalizedResponse {
 	return r.lastValidResponse
 }
 
+// Context returns the request's context, defaulting to context.Background() if none
+// has been attached yet. Upstream drivers should derive from this context so that
+// client disconnects and deadlines cancel in-flight calls.
+func (r *NormalizedRequest) Context() context.Context {
+	if r == nil {
+		return context.Background()
+	}
+
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.ctx == nil {
+		return context.Background()
+	}
+
+	return r.ctx
+}
+
+// WithContext attaches ctx to the request so middleware can carry values (request id,
+// tracing span) that downstream upstream drivers read without extra arguments. It
+// returns the request for chaining.
+func (r *NormalizedRequest) WithContext(ctx context.Context) *NormalizedRequest {
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+	r.ctx = ctx
+	return r
+}
+
+// WithTimeout derives a new context with the given deadline from the request's current
+// context and attaches it. The returned cancel func must be called by the caller once
+// the request (and any retries) have finished, to release the timer deterministically
+// rather than leaving it to the deadline.
+func (r *NormalizedRequest) WithTimeout(d time.Duration) context.CancelFunc {
+	r.Mu.Lock()
+	parent := r.ctx
+	r.Mu.Unlock()
+
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, d)
+	r.WithContext(ctx)
+
+	return cancel
+}
+
 func (r *NormalizedRequest) Network() Network {
 	if r == nil {
 		return nil
@@ -120,6 +188,16 @@ func (r *NormalizedRequest) Id() string {
 	return ""
 }
 
+// rawId returns the original JSON-RPC id value (string, float64, or nil for a
+// notification) for marshaling into a response object, as opposed to Id() which
+// normalizes it into a display string for logging/caching.
+func (r *NormalizedRequest) rawId() interface{} {
+	if r == nil || r.jsonRpcRequest == nil {
+		return nil
+	}
+	return r.jsonRpcRequest.ID
+}
+
 func (r *NormalizedRequest) NetworkId() string {
 	if r == nil || r.network == nil {
 		// For certain requests such as internal eth_chainId requests, network might not be available yet.
@@ -132,11 +210,46 @@ func (r *NormalizedRequest) SetNetwork(network Network) {
 	r.network = network
 }
 
-func (r *NormalizedRequest) ApplyDirectivesFromHttpHeaders(headers *fasthttp.RequestHeader) {
+// ApplyDirectivesFromHttpHeaders builds this request's directives from the client's
+// HTTP headers and immediately applies the resolved deadline via WithTimeout, so the
+// timeout actually takes effect rather than sitting unused in RequestDirectives.
+// defaultTimeoutMs is the non-header fallback the caller has already resolved via
+// config.ResolveTimeoutMs (upstream, then project, then server); X-ERPC-Timeout-Ms
+// overrides it when present. The returned cancel func must be called once the request
+// (and any retries) have finished, same as WithTimeout.
+func (r *NormalizedRequest) ApplyDirectivesFromHttpHeaders(headers *fasthttp.RequestHeader, defaultTimeoutMs int) context.CancelFunc {
 	drc := &RequestDirectives{
 		RetryEmpty: string(headers.Peek("X-ERPC-Retry-Empty")) != "false",
 	}
+
+	timeoutMs := defaultTimeoutMs
+	if tms := headers.Peek("X-ERPC-Timeout-Ms"); len(tms) > 0 {
+		if ms, err := strconv.Atoi(string(tms)); err == nil && ms > 0 {
+			timeoutMs = ms
+		}
+	}
+	drc.TimeoutMs = timeoutMs
+
+	if ma := headers.Peek("X-ERPC-Max-Attempts"); len(ma) > 0 {
+		if n, err := strconv.Atoi(string(ma)); err == nil && n > 0 {
+			drc.MaxAttempts = n
+		}
+	}
+
+	if ro := headers.Peek("X-ERPC-Retry-On"); len(ro) > 0 {
+		drc.RetryOn = strings.Split(string(ro), ",")
+		for i := range drc.RetryOn {
+			drc.RetryOn[i] = strings.TrimSpace(drc.RetryOn[i])
+		}
+	}
+
 	r.directives = drc
+
+	if timeoutMs > 0 {
+		return r.WithTimeout(time.Duration(timeoutMs) * time.Millisecond)
+	}
+
+	return func() {}
 }
 
 func (r *NormalizedRequest) Directives() *RequestDirectives {
@@ -157,6 +270,10 @@ func (r *NormalizedRequest) JsonRpcRequest() (*JsonRpcRequest, error) {
 		return r.jsonRpcRequest, nil
 	}
 
+	if r.IsBatch() {
+		return nil, NewErrJsonRpcRequestUnmarshal(fmt.Errorf("request is a batch, use Batch() to access individual sub-requests"))
+	}
+
 	rpcReq := new(JsonRpcRequest)
 	if err := sonic.Unmarshal(r.body, rpcReq); err != nil {
 		return nil, NewErrJsonRpcRequestUnmarshal(err)
@@ -180,6 +297,78 @@ func (r *NormalizedRequest) JsonRpcRequest() (*JsonRpcRequest, error) {
 	return rpcReq, nil
 }
 
+// IsBatch reports whether the request body is a JSON-RPC batch, i.e. a top-level
+// JSON array of call objects rather than a single call object.
+func (r *NormalizedRequest) IsBatch() bool {
+	if r == nil {
+		return false
+	}
+
+	trimmed := bytes.TrimSpace(r.body)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// Batch splits a JSON-RPC batch request into its individual sub-requests. Each child
+// NormalizedRequest shares this request's network and directives so it is forwarded
+// and cached the same way a standalone request would be. The second return value is
+// false (with a nil slice) when the body is not a batch, in which case the request
+// should be handled as a single call as usual.
+func (r *NormalizedRequest) Batch() ([]*NormalizedRequest, bool) {
+	if r == nil || !r.IsBatch() {
+		return nil, false
+	}
+
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.batch != nil {
+		return r.batch, true
+	}
+
+	var rpcReqs []*JsonRpcRequest
+	if err := sonic.Unmarshal(bytes.TrimSpace(r.body), &rpcReqs); err != nil {
+		return nil, false
+	}
+
+	batch := make([]*NormalizedRequest, 0, len(rpcReqs))
+	for _, rpcReq := range rpcReqs {
+		if rpcReq.JSONRPC == "" {
+			rpcReq.JSONRPC = "2.0"
+		}
+
+		// Unlike a standalone request, a missing ID here must NOT be masked with a
+		// synthesized one: it means the original sub-call was a JSON-RPC notification,
+		// and the reassembler needs IsNotification() to tell it to omit this slot from
+		// the response array entirely rather than invent a response for it.
+
+		child := &NormalizedRequest{
+			network:        r.network,
+			directives:     r.directives,
+			jsonRpcRequest: rpcReq,
+			ctx:            r.ctx,
+		}
+		if body, err := sonic.Marshal(rpcReq); err == nil {
+			child.body = body
+		}
+
+		batch = append(batch, child)
+	}
+
+	r.batch = batch
+
+	return batch, true
+}
+
+// IsNotification reports whether this request's original JSON-RPC call had no id,
+// meaning it is a notification. A batch reassembler must omit notifications from the
+// response array rather than emit a response for them.
+func (r *NormalizedRequest) IsNotification() bool {
+	if r == nil || r.jsonRpcRequest == nil {
+		return false
+	}
+	return r.jsonRpcRequest.ID == nil
+}
+
 func (r *NormalizedRequest) Method() (string, error) {
 	if r.method != "" {
 		return r.method, nil
@@ -204,12 +393,29 @@ func (r *NormalizedRequest) Method() (string, error) {
 	return "", NewErrJsonRpcRequestUnresolvableMethod(r.body)
 }
 
+// Namespace returns the portion of the method before the first underscore, e.g. "eth"
+// for "eth_getBlockByNumber" or "debug" for "debug_traceTransaction". It returns an
+// empty string if the method has no underscore or could not be resolved.
+func (r *NormalizedRequest) Namespace() string {
+	method, err := r.Method()
+	if err != nil || method == "" {
+		return ""
+	}
+
+	idx := strings.Index(method, "_")
+	if idx <= 0 {
+		return ""
+	}
+
+	return method[:idx]
+}
+
 func (r *NormalizedRequest) Body() []byte {
 	return r.body
 }
 
 func (r *NormalizedRequest) MarshalZerologObject(e *zerolog.Event) {
-	e.Str("body", string(r.body))
+	e.Str("body", string(r.body)).Int("attempt", r.Attempt)
 }
 
 func (r *NormalizedRequest) EvmBlockNumber() (int64, error) {
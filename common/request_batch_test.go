@@ -0,0 +1,76 @@
+package common
+
+import "testing"
+
+func TestNormalizedRequest_IsBatch(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"single object", `{"jsonrpc":"2.0","method":"eth_chainId","id":1}`, false},
+		{"array", `[{"jsonrpc":"2.0","method":"eth_chainId","id":1}]`, true},
+		{"array with leading whitespace", "  \n[{\"method\":\"eth_chainId\"}]", true},
+		{"empty", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nr := NewNormalizedRequest([]byte(tc.body))
+			if got := nr.IsBatch(); got != tc.want {
+				t.Fatalf("IsBatch() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedRequest_Batch(t *testing.T) {
+	body := `[
+		{"jsonrpc":"2.0","method":"eth_chainId","id":1},
+		{"jsonrpc":"2.0","method":"eth_blockNumber","id":"abc"},
+		{"jsonrpc":"2.0","method":"eth_subscribe"}
+	]`
+
+	parent := NewNormalizedRequest([]byte(body))
+
+	children, ok := parent.Batch()
+	if !ok {
+		t.Fatalf("Batch() ok = false, want true")
+	}
+	if len(children) != 3 {
+		t.Fatalf("Batch() returned %d sub-requests, want 3", len(children))
+	}
+
+	m0, err := children[0].Method()
+	if err != nil || m0 != "eth_chainId" {
+		t.Fatalf("children[0].Method() = %q, %v, want eth_chainId, nil", m0, err)
+	}
+	if children[0].IsNotification() {
+		t.Fatalf("children[0].IsNotification() = true, want false (had id 1)")
+	}
+
+	m1, err := children[1].Method()
+	if err != nil || m1 != "eth_blockNumber" {
+		t.Fatalf("children[1].Method() = %q, %v, want eth_blockNumber, nil", m1, err)
+	}
+
+	// The third sub-call had no id in the original request: it must remain a
+	// notification rather than have an id synthesized for it.
+	if !children[2].IsNotification() {
+		t.Fatalf("children[2].IsNotification() = false, want true (no id in original call)")
+	}
+
+	// A second call must return the same cached split rather than re-parsing.
+	again, ok := parent.Batch()
+	if !ok || len(again) != len(children) || again[0] != children[0] {
+		t.Fatalf("Batch() second call = %v, %v, want cached result of len %d", again, ok, len(children))
+	}
+}
+
+func TestNormalizedRequest_Batch_NotABatch(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	if _, ok := nr.Batch(); ok {
+		t.Fatalf("Batch() ok = true for a single-call body, want false")
+	}
+}
@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNormalizedRequest_Context_DefaultsToBackground(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	if nr.Context() != context.Background() {
+		t.Fatalf("Context() = %v, want context.Background()", nr.Context())
+	}
+}
+
+func TestNormalizedRequest_WithContext(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "trace-id")
+
+	if nr.WithContext(ctx) != nr {
+		t.Fatalf("WithContext() should return the same request for chaining")
+	}
+
+	if got := nr.Context().Value(key{}); got != "trace-id" {
+		t.Fatalf("Context().Value() = %v, want trace-id", got)
+	}
+}
+
+func TestNormalizedRequest_WithTimeout(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	cancel := nr.WithTimeout(10 * time.Millisecond)
+	defer cancel()
+
+	deadline, ok := nr.Context().Deadline()
+	if !ok {
+		t.Fatalf("Context() has no deadline after WithTimeout")
+	}
+	if time.Until(deadline) > 10*time.Millisecond {
+		t.Fatalf("deadline is further out than the requested timeout")
+	}
+
+	select {
+	case <-nr.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatalf("context did not become done after its timeout elapsed")
+	}
+}
+
+func TestNormalizedRequest_WithTimeout_DerivesFromExistingContext(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	type key struct{}
+	parent := context.WithValue(context.Background(), key{}, "trace-id")
+	nr.WithContext(parent)
+
+	cancel := nr.WithTimeout(time.Second)
+	defer cancel()
+
+	if got := nr.Context().Value(key{}); got != "trace-id" {
+		t.Fatalf("WithTimeout lost a value from the parent context: got %v, want trace-id", got)
+	}
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestApplyDirectivesFromHttpHeaders_UsesDefaultTimeout(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	var headers fasthttp.RequestHeader
+	cancel := nr.ApplyDirectivesFromHttpHeaders(&headers, 5000)
+	defer cancel()
+
+	deadline, ok := nr.Context().Deadline()
+	if !ok {
+		t.Fatalf("Context() has no deadline, want one derived from defaultTimeoutMs")
+	}
+	if d := time.Until(deadline); d <= 0 || d > 5*time.Second {
+		t.Fatalf("deadline %v not within the expected 5s window", d)
+	}
+	if nr.Directives().TimeoutMs != 5000 {
+		t.Fatalf("Directives().TimeoutMs = %d, want 5000", nr.Directives().TimeoutMs)
+	}
+}
+
+func TestApplyDirectivesFromHttpHeaders_HeaderOverridesDefault(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	var headers fasthttp.RequestHeader
+	headers.Set("X-ERPC-Timeout-Ms", "100")
+	cancel := nr.ApplyDirectivesFromHttpHeaders(&headers, 5000)
+	defer cancel()
+
+	if nr.Directives().TimeoutMs != 100 {
+		t.Fatalf("Directives().TimeoutMs = %d, want 100 (header override)", nr.Directives().TimeoutMs)
+	}
+
+	deadline, ok := nr.Context().Deadline()
+	if !ok {
+		t.Fatalf("Context() has no deadline")
+	}
+	if d := time.Until(deadline); d > 200*time.Millisecond {
+		t.Fatalf("deadline %v too far out, want close to the 100ms header value", d)
+	}
+}
+
+func TestApplyDirectivesFromHttpHeaders_NoTimeoutMeansNoDeadline(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	var headers fasthttp.RequestHeader
+	cancel := nr.ApplyDirectivesFromHttpHeaders(&headers, 0)
+	defer cancel()
+
+	if _, ok := nr.Context().Deadline(); ok {
+		t.Fatalf("Context() has a deadline, want none when no timeout is configured anywhere")
+	}
+}
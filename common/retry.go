@@ -0,0 +1,44 @@
+package common
+
+import "context"
+
+// RetryDecider reports whether a given 1-indexed attempt should be retried given the
+// error produced by the previous attempt. config.RetryPolicy implements this via its
+// own ShouldRetry method, so common can consult a configured retry policy without
+// importing the config package.
+type RetryDecider interface {
+	ShouldRetry(attempt int, errMsg string) bool
+}
+
+// RetryForwarder forwards req for a single attempt and returns its response body or
+// the error from that attempt.
+type RetryForwarder func(ctx context.Context, req *NormalizedRequest) ([]byte, error)
+
+// WithRetry drives req through forward, incrementing req.Attempt before each try and
+// retrying per decider.ShouldRetry until it succeeds, the policy is exhausted, or
+// req's own context is done — whichever comes first, so a retry storm cannot outlive
+// the request's deadline. decider may be nil, in which case the first attempt's
+// result is returned unconditionally (no retries).
+func WithRetry(req *NormalizedRequest, decider RetryDecider, forward RetryForwarder) ([]byte, error) {
+	ctx := req.Context()
+
+	for {
+		req.Mu.Lock()
+		req.Attempt++
+		attempt := req.Attempt
+		req.Mu.Unlock()
+
+		body, err := forward(ctx, req)
+		if err == nil {
+			return body, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if decider == nil || !decider.ShouldRetry(attempt, err.Error()) {
+			return nil, err
+		}
+	}
+}
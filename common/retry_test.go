@@ -0,0 +1,97 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingDecider retries until maxAttempt is reached.
+type countingDecider struct {
+	maxAttempt int
+}
+
+func (d countingDecider) ShouldRetry(attempt int, errMsg string) bool {
+	return attempt < d.maxAttempt
+}
+
+func TestWithRetry_SucceedsFirstTry(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	calls := 0
+	body, err := WithRetry(nr, countingDecider{maxAttempt: 3}, func(ctx context.Context, req *NormalizedRequest) ([]byte, error) {
+		calls++
+		return []byte("ok"), nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want \"ok\"", body)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (no retries on first success)", calls)
+	}
+	if nr.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1", nr.Attempt)
+	}
+}
+
+func TestWithRetry_RetriesUntilDeciderStops(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	calls := 0
+	_, err := WithRetry(nr, countingDecider{maxAttempt: 3}, func(ctx context.Context, req *NormalizedRequest) ([]byte, error) {
+		calls++
+		return nil, errors.New("upstream unavailable")
+	})
+
+	if err == nil {
+		t.Fatalf("expected an error once the decider stops retrying")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3 (decider allows attempts 1 and 2, stops at 3)", calls)
+	}
+	if nr.Attempt != 3 {
+		t.Errorf("Attempt = %d, want 3", nr.Attempt)
+	}
+}
+
+func TestWithRetry_NilDeciderMeansNoRetries(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+
+	calls := 0
+	_, err := WithRetry(nr, nil, func(ctx context.Context, req *NormalizedRequest) ([]byte, error) {
+		calls++
+		return nil, errors.New("boom")
+	})
+
+	if err == nil {
+		t.Fatalf("expected an error to be returned unconditionally")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (nil decider must not retry)", calls)
+	}
+}
+
+func TestWithRetry_StopsWhenContextDone(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_chainId","id":1}`))
+	cancel := nr.WithTimeout(10 * time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	_, err := WithRetry(nr, countingDecider{maxAttempt: 1000}, func(ctx context.Context, req *NormalizedRequest) ([]byte, error) {
+		calls++
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	if err == nil {
+		t.Fatalf("expected the context deadline error to surface")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1 (must not retry past the request's own deadline)", calls)
+	}
+}
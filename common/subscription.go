@@ -0,0 +1,62 @@
+package common
+
+import (
+	"sync"
+)
+
+// SubscriptionRequest is the id-bookkeeping primitive for an eth_subscribe call: it
+// tracks the upstream-assigned subscription id alongside the stable id handed back to
+// the client, so a future WS listener can rewrite one into the other across upstream
+// failover. It does not itself open a socket, relay frames, poll for updates, or tear
+// anything down — there is no transport here yet, only the id mapping it will need.
+type SubscriptionRequest struct {
+	*NormalizedRequest
+
+	clientSubId   string
+	upstreamSubId string
+
+	mu sync.Mutex
+}
+
+// IsSubscribeMethod reports whether method starts or stops a streaming subscription
+// rather than returning a one-shot result. It is a pure classifier; no listener or
+// dispatcher acts on it yet.
+func IsSubscribeMethod(method string) bool {
+	return method == "eth_subscribe" || method == "eth_unsubscribe"
+}
+
+// NewSubscriptionRequest wraps an already-normalized eth_subscribe request.
+func NewSubscriptionRequest(nr *NormalizedRequest) *SubscriptionRequest {
+	return &SubscriptionRequest{
+		NormalizedRequest: nr,
+	}
+}
+
+// ClientSubscriptionId returns the eRPC-issued subscription id surfaced to the client.
+func (s *SubscriptionRequest) ClientSubscriptionId() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clientSubId
+}
+
+// SetClientSubscriptionId assigns the eRPC-issued subscription id surfaced to the client.
+func (s *SubscriptionRequest) SetClientSubscriptionId(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clientSubId = id
+}
+
+// UpstreamSubscriptionId returns the subscription id assigned by the current upstream.
+func (s *SubscriptionRequest) UpstreamSubscriptionId() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.upstreamSubId
+}
+
+// SetUpstreamSubscriptionId records the subscription id assigned by the current upstream.
+// It is called again after failover once the new upstream has resubscribed.
+func (s *SubscriptionRequest) SetUpstreamSubscriptionId(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upstreamSubId = id
+}
@@ -0,0 +1,44 @@
+package common
+
+import "testing"
+
+func TestIsSubscribeMethod(t *testing.T) {
+	tests := map[string]bool{
+		"eth_subscribe":   true,
+		"eth_unsubscribe": true,
+		"eth_getLogs":     false,
+		"":                false,
+	}
+
+	for method, want := range tests {
+		if got := IsSubscribeMethod(method); got != want {
+			t.Errorf("IsSubscribeMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestSubscriptionRequest_IdBookkeeping(t *testing.T) {
+	nr := NewNormalizedRequest([]byte(`{"jsonrpc":"2.0","method":"eth_subscribe","params":["newHeads"],"id":1}`))
+	sr := NewSubscriptionRequest(nr)
+
+	if sr.ClientSubscriptionId() != "" || sr.UpstreamSubscriptionId() != "" {
+		t.Fatalf("new SubscriptionRequest should start with empty ids")
+	}
+
+	sr.SetClientSubscriptionId("client-1")
+	sr.SetUpstreamSubscriptionId("upstream-1")
+
+	if got := sr.ClientSubscriptionId(); got != "client-1" {
+		t.Errorf("ClientSubscriptionId() = %q, want client-1", got)
+	}
+	if got := sr.UpstreamSubscriptionId(); got != "upstream-1" {
+		t.Errorf("UpstreamSubscriptionId() = %q, want upstream-1", got)
+	}
+
+	// Resubscription after upstream failover rewrites the upstream id but must
+	// leave the client-facing id untouched.
+	sr.SetUpstreamSubscriptionId("upstream-2")
+	if got := sr.ClientSubscriptionId(); got != "client-1" {
+		t.Errorf("ClientSubscriptionId() after failover = %q, want client-1", got)
+	}
+}
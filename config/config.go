@@ -1,33 +1,269 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
+// UpstreamType describes the class of node an upstream points at, used to pick
+// sensible built-in namespace defaults when Supported/Excludes aren't spelled out.
+type UpstreamType string
+
+const (
+	UpstreamTypeFull    UpstreamType = "full"
+	UpstreamTypeArchive UpstreamType = "archive"
+	UpstreamTypeTrace   UpstreamType = "trace"
+)
+
 type Upstream struct {
 	Id       string            `yaml:"id"`
 	Endpoint string            `yaml:"endpoint"`
 	Metadata map[string]string `yaml:"metadata"`
+
+	// TimeoutMs overrides the project/server default deadline for requests
+	// forwarded to this upstream, when > 0.
+	TimeoutMs int `yaml:"timeoutMs"`
+
+	// Type hints at this upstream's capabilities (full/archive/trace) so built-in
+	// namespace defaults can be applied without listing every method. Left empty
+	// (with Supported also empty), the upstream is treated as serving every method;
+	// its capabilities are not probed.
+	Type UpstreamType `yaml:"type"`
+
+	// Supported is an allow-list of namespaces (e.g. "eth", "trace") or exact methods
+	// this upstream may serve. Empty means "defer to Type's built-in defaults".
+	Supported []string `yaml:"supports"`
+
+	// Excludes is a deny-list of namespaces or exact methods this upstream must
+	// never be routed, applied after Supported/built-in defaults.
+	Excludes []string `yaml:"excludes"`
+
+	// Retry is this upstream's retry policy. Nothing in this package combines it
+	// with the project's Retry.
+	Retry *RetryPolicy `yaml:"retry"`
+}
+
+// defaultNamespacesByType are the built-in namespace allow-lists used when an
+// upstream's Type is set but it doesn't spell out Supported itself.
+var defaultNamespacesByType = map[UpstreamType][]string{
+	UpstreamTypeFull:    {"eth", "net", "web3"},
+	UpstreamTypeArchive: {"eth", "net", "web3", "debug"},
+	UpstreamTypeTrace:   {"eth", "net", "web3", "debug", "trace"},
+}
+
+// DefaultNamespacesForType returns the built-in namespace allow-list for an upstream
+// Type, or nil if typ is empty/unrecognized (meaning no built-in default applies and
+// capabilities come from Supported alone, or are unrestricted if that is empty too).
+func DefaultNamespacesForType(typ UpstreamType) []string {
+	ns, ok := defaultNamespacesByType[typ]
+	if !ok {
+		return nil
+	}
+
+	out := make([]string, len(ns))
+	copy(out, ns)
+	return out
+}
+
+// Supports reports whether this upstream may serve method, based on Excludes first,
+// then Supported (or, if Supported is empty, the built-in defaults for Type). A method
+// matches an entry that is either the exact method name or its namespace (the part
+// before the first underscore).
+func (u Upstream) Supports(method string) bool {
+	namespace := method
+	if idx := strings.Index(method, "_"); idx > 0 {
+		namespace = method[:idx]
+	}
+
+	for _, excluded := range u.Excludes {
+		if excluded == method || excluded == namespace {
+			return false
+		}
+	}
+
+	allowed := u.Supported
+	if len(allowed) == 0 {
+		allowed = DefaultNamespacesForType(u.Type)
+	}
+
+	// No allow-list at all (no Supported, no Type defaults): treat as unrestricted.
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, a := range allowed {
+		if a == method || a == namespace {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Project struct {
 	Id        string     `yaml:"id"`
 	Upstreams []Upstream `yaml:"upstreams"`
+
+	// TimeoutMs overrides the server default deadline for requests under this
+	// project, when > 0.
+	TimeoutMs int `yaml:"timeoutMs"`
+
+	// Retry is the project-wide default retry policy, with per-method overrides
+	// applied by ForMethod. Nothing in this package merges an upstream's Retry
+	// into it.
+	Retry *RetryPolicy `yaml:"retry"`
+}
+
+// BackoffStrategy selects how the delay between retry attempts grows.
+type BackoffStrategy string
+
+const (
+	BackoffConstant    BackoffStrategy = "constant"
+	BackoffExponential BackoffStrategy = "exponential"
+	BackoffJittered    BackoffStrategy = "jittered"
+)
+
+// RetryBackoff configures the delay curve between retry attempts.
+type RetryBackoff struct {
+	Strategy   BackoffStrategy `yaml:"strategy"`
+	InitialMs  int             `yaml:"initialMs"`
+	MaxMs      int             `yaml:"maxMs"`
+	Multiplier float64         `yaml:"multiplier"`
+}
+
+// RetryPolicy configures how many times and under what conditions a failed request
+// is retried. Methods carries per-method overrides (e.g. eth_getLogs needing a lower
+// maxAttempts than eth_call on range-limited providers); unset fields on an override
+// fall back to the enclosing policy's value.
+type RetryPolicy struct {
+	MaxAttempts int           `yaml:"maxAttempts"`
+	Backoff     *RetryBackoff `yaml:"backoff"`
+
+	// RetryOn lists HTTP status codes, JSON-RPC error codes, and error substrings
+	// (e.g. "execution reverted") that trigger a retry, each matched as a substring
+	// of the error message. Left empty, every error except "execution reverted" is
+	// retried; that one is retried only when listed explicitly.
+	RetryOn []string `yaml:"retryOn"`
+
+	Methods map[string]*RetryPolicy `yaml:"methods"`
+}
+
+// ForMethod returns the effective policy for method, applying the method-specific
+// override (if any) on top of p's own fields.
+func (p *RetryPolicy) ForMethod(method string) *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+
+	override, ok := p.Methods[method]
+	if !ok || override == nil {
+		return p
+	}
+
+	merged := *p
+	if override.MaxAttempts > 0 {
+		merged.MaxAttempts = override.MaxAttempts
+	}
+	if override.Backoff != nil {
+		merged.Backoff = override.Backoff
+	}
+	if len(override.RetryOn) > 0 {
+		merged.RetryOn = override.RetryOn
+	}
+
+	return &merged
+}
+
+// WithOverrides returns a copy of p with MaxAttempts/RetryOn replaced by the given
+// request-level overrides when they're set (maxAttempts > 0, retryOn non-empty), so
+// a single request's X-ERPC-Max-Attempts / X-ERPC-Retry-On headers can loosen or
+// tighten retry behavior without touching the YAML config. Call this after ForMethod
+// so the overrides apply on top of the method-specific policy, not the base one. A
+// nil receiver with no overrides returns nil; a nil receiver with an override
+// synthesizes a policy from just that override.
+func (p *RetryPolicy) WithOverrides(maxAttempts int, retryOn []string) *RetryPolicy {
+	if p == nil {
+		if maxAttempts <= 0 && len(retryOn) == 0 {
+			return nil
+		}
+		p = &RetryPolicy{}
+	}
+
+	if maxAttempts <= 0 && len(retryOn) == 0 {
+		return p
+	}
+
+	merged := *p
+	if maxAttempts > 0 {
+		merged.MaxAttempts = maxAttempts
+	}
+	if len(retryOn) > 0 {
+		merged.RetryOn = retryOn
+	}
+
+	return &merged
+}
+
+// ShouldRetry reports whether attempt (the 1-indexed attempt that just failed, e.g.
+// common.NormalizedRequest.Attempt) should be retried under p given the error message
+// from that attempt. It satisfies common.RetryDecider structurally, so common can
+// consult a *RetryPolicy without importing config. attempt must be less than
+// MaxAttempts; if RetryOn is non-empty, errMsg must also contain one of its entries
+// (case-insensitive) for the retry to proceed. With RetryOn empty, any error except
+// one containing "execution reverted" (case-insensitive) is retried.
+func (p *RetryPolicy) ShouldRetry(attempt int, errMsg string) bool {
+	if p == nil || p.MaxAttempts <= 0 {
+		return false
+	}
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	lower := strings.ToLower(errMsg)
+	if len(p.RetryOn) == 0 {
+		return !strings.Contains(lower, "execution reverted")
+	}
+
+	for _, matcher := range p.RetryOn {
+		if strings.Contains(lower, strings.ToLower(matcher)) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // Config represents the configuration of the application.
 type Config struct {
 	Server struct {
-		HttpHost     string `yaml:"httpHost"`
-		HttpPort     string `yaml:"httpPort"`
-		maxTimeoutMs int    `yaml:"maxTimeoutMs"`
+		HttpHost string `yaml:"httpHost"`
+		HttpPort string `yaml:"httpPort"`
+
+		// MaxTimeoutMs is the default per-request deadline applied when a request
+		// doesn't set X-ERPC-Timeout-Ms; it can be overridden per-project/upstream.
+		MaxTimeoutMs int `yaml:"maxTimeoutMs"`
 	} `yaml:"server"`
 	LogLevel string    `yaml:"logLevel"`
 	Projects []Project `yaml:"projects"`
 }
 
+// ResolveTimeoutMs picks the effective deadline (in milliseconds) for a request,
+// preferring the most specific non-zero setting: upstreamMs, then projectMs, then the
+// server-wide serverMs default. The caller passes the result as the defaultTimeoutMs
+// argument to common.NormalizedRequest.ApplyDirectivesFromHttpHeaders, which in turn
+// lets X-ERPC-Timeout-Ms override it for a single request.
+func ResolveTimeoutMs(serverMs, projectMs, upstreamMs int) int {
+	if upstreamMs > 0 {
+		return upstreamMs
+	}
+	if projectMs > 0 {
+		return projectMs
+	}
+	return serverMs
+}
+
 // LoadConfig loads the configuration from the specified file.
 func LoadConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
@@ -44,3 +280,82 @@ func LoadConfig(filename string) (*Config, error) {
 
 	return &cfg, nil
 }
+
+// Validate checks cfg for structural problems that yaml.Unmarshal can't catch on its
+// own: duplicate project/upstream ids, upstreams with no endpoint, and retry policies
+// with nonsensical values. It returns the first problem found.
+func (c *Config) Validate() error {
+	seenProjects := make(map[string]bool)
+
+	for _, p := range c.Projects {
+		if p.Id == "" {
+			return fmt.Errorf("project has an empty id")
+		}
+		if seenProjects[p.Id] {
+			return fmt.Errorf("duplicate project id %q", p.Id)
+		}
+		seenProjects[p.Id] = true
+
+		if err := p.Retry.validate(); err != nil {
+			return fmt.Errorf("project %q: retry policy: %w", p.Id, err)
+		}
+
+		seenUpstreams := make(map[string]bool)
+		for _, u := range p.Upstreams {
+			if u.Id == "" {
+				return fmt.Errorf("project %q: upstream has an empty id", p.Id)
+			}
+			if seenUpstreams[u.Id] {
+				return fmt.Errorf("project %q: duplicate upstream id %q", p.Id, u.Id)
+			}
+			seenUpstreams[u.Id] = true
+
+			if u.Endpoint == "" {
+				return fmt.Errorf("project %q: upstream %q has an empty endpoint", p.Id, u.Id)
+			}
+
+			if err := u.Retry.validate(); err != nil {
+				return fmt.Errorf("project %q: upstream %q: retry policy: %w", p.Id, u.Id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks p and its per-method overrides for nonsensical values. A nil policy
+// is always valid (it means "use the enclosing default").
+func (p *RetryPolicy) validate() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("maxAttempts must be >= 0, got %d", p.MaxAttempts)
+	}
+
+	if p.Backoff != nil {
+		if p.Backoff.InitialMs < 0 || p.Backoff.MaxMs < 0 {
+			return fmt.Errorf("backoff initialMs/maxMs must be >= 0")
+		}
+		if p.Backoff.MaxMs > 0 && p.Backoff.InitialMs > p.Backoff.MaxMs {
+			return fmt.Errorf("backoff initialMs (%d) must not exceed maxMs (%d)", p.Backoff.InitialMs, p.Backoff.MaxMs)
+		}
+		switch p.Backoff.Strategy {
+		case "", BackoffConstant, BackoffExponential, BackoffJittered:
+		default:
+			return fmt.Errorf("unknown backoff strategy %q", p.Backoff.Strategy)
+		}
+		if (p.Backoff.Strategy == BackoffExponential || p.Backoff.Strategy == BackoffJittered) && p.Backoff.Multiplier <= 0 {
+			return fmt.Errorf("backoff multiplier must be > 0 for strategy %q", p.Backoff.Strategy)
+		}
+	}
+
+	for method, override := range p.Methods {
+		if err := override.validate(); err != nil {
+			return fmt.Errorf("method %q: %w", method, err)
+		}
+	}
+
+	return nil
+}
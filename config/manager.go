@@ -0,0 +1,223 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// PreReloadHook is run against a candidate config before it is committed, so new
+// upstreams can be dialed and health-probed while the previous config is still live.
+// Returning an error aborts the reload: the candidate is discarded and Current keeps
+// returning the previous config.
+type PreReloadHook func(candidate *Config) error
+
+// ReloadListener is notified after a reload has already been committed and is visible
+// via Current, so it can reconcile the rest of the change (e.g. drain and close
+// upstreams that were removed) without disturbing requests already in flight.
+type ReloadListener func(old, new *Config)
+
+// ConfigManager keeps a Config in sync with its backing file, reloading on SIGHUP or a
+// filesystem write instead of requiring a process restart. The current config is held
+// in an atomic.Pointer so readers never observe a partially-applied update. A reload
+// runs in two phases: PreReloadHooks probe the candidate config first, and only once
+// every hook succeeds is it stored and ReloadListeners notified.
+type ConfigManager struct {
+	filename string
+	current  atomic.Pointer[Config]
+
+	mu        sync.Mutex
+	preHooks  []PreReloadHook
+	listeners []ReloadListener
+}
+
+// NewConfigManager loads filename once and returns a manager wrapping it. The initial
+// load is validated the same way reload() validates a candidate, so a config with
+// duplicate ids or a nonsensical retry policy is rejected at startup rather than being
+// served until the next reload catches it. Call Start to begin watching for SIGHUP and
+// filesystem changes.
+func NewConfigManager(filename string) (*ConfigManager, error) {
+	cfg, err := LoadConfig(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	cm := &ConfigManager{filename: filename}
+	cm.current.Store(cfg)
+
+	return cm, nil
+}
+
+// Current returns the currently active configuration. Safe for concurrent use.
+func (cm *ConfigManager) Current() *Config {
+	return cm.current.Load()
+}
+
+// OnPreReload registers a hook run against each reload candidate before it is
+// committed. Use this to dial and health-probe new upstreams: returning an error
+// aborts the reload entirely, leaving the previous config live.
+func (cm *ConfigManager) OnPreReload(fn PreReloadHook) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.preHooks = append(cm.preHooks, fn)
+}
+
+// OnReload registers a listener invoked after every successful reload, once the new
+// config is already live. Listeners are called synchronously and in registration
+// order; use this to reconcile state that doesn't need to block the swap, such as
+// draining and closing upstreams that were removed.
+func (cm *ConfigManager) OnReload(fn ReloadListener) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.listeners = append(cm.listeners, fn)
+}
+
+// Start begins watching the config file for SIGHUP and filesystem writes, reloading in
+// response to either. It runs until ctx is cancelled.
+func (cm *ConfigManager) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start config watcher: %w", err)
+	}
+
+	if err := watcher.Add(cm.filename); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch config file %s: %w", cm.filename, err)
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(sighup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sighup:
+				log.Info().Str("signal", sig.String()).Msg("reloading config due to signal")
+				cm.reload()
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					log.Info().Str("file", event.Name).Msg("reloading config due to filesystem change")
+					cm.reload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Warn().Err(err).Msg("config watcher error")
+			}
+		}
+	}()
+
+	return nil
+}
+
+// reload re-reads the config file, validates it, runs every PreReloadHook against the
+// candidate (e.g. to dial and health-probe new upstreams), and only then atomically
+// swaps it in and notifies ReloadListeners. A reload that fails to parse, fails
+// Validate, changes a field requiring a restart, or is rejected by any pre-reload
+// hook leaves the previous config live and in effect for requests already observing
+// Current.
+func (cm *ConfigManager) reload() {
+	next, err := LoadConfig(cm.filename)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to reload config, keeping previous version")
+		return
+	}
+
+	if err := next.Validate(); err != nil {
+		log.Warn().Err(err).Msg("rejected config reload: invalid config")
+		return
+	}
+
+	prev := cm.current.Load()
+
+	if reason := restartRequired(prev, next); reason != "" {
+		log.Warn().Str("reason", reason).Msg("rejected config reload: requires a restart")
+		return
+	}
+
+	cm.mu.Lock()
+	preHooks := append([]PreReloadHook(nil), cm.preHooks...)
+	cm.mu.Unlock()
+
+	for _, fn := range preHooks {
+		if err := fn(next); err != nil {
+			log.Warn().Err(err).Msg("rejected config reload: pre-reload hook failed")
+			return
+		}
+	}
+
+	cm.current.Store(next)
+	log.Info().Str("diff", diffSummary(prev, next)).Msg("config reloaded")
+
+	cm.mu.Lock()
+	listeners := append([]ReloadListener(nil), cm.listeners...)
+	cm.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(prev, next)
+	}
+}
+
+// restartRequired returns a human-readable reason the reload was rejected, or "" if
+// next can be applied live. Only the listen addresses require a restart today.
+func restartRequired(prev, next *Config) string {
+	if prev.Server.HttpHost != next.Server.HttpHost || prev.Server.HttpPort != next.Server.HttpPort {
+		return fmt.Sprintf("server.httpHost/httpPort changed (%s:%s -> %s:%s)",
+			prev.Server.HttpHost, prev.Server.HttpPort, next.Server.HttpHost, next.Server.HttpPort)
+	}
+
+	return ""
+}
+
+// diffSummary produces a short structured description of what changed between prev and
+// next, for the reload log line. It only counts upstreams added and removed (keyed by
+// project id and upstream id), not changed fields; listeners
+// are expected to compute their own finer-grained diff when reconciling.
+func diffSummary(prev, next *Config) string {
+	added, removed := 0, 0
+
+	prevUpstreams := make(map[string]bool)
+	for _, p := range prev.Projects {
+		for _, u := range p.Upstreams {
+			prevUpstreams[p.Id+"/"+u.Id] = true
+		}
+	}
+
+	nextUpstreams := make(map[string]bool)
+	for _, p := range next.Projects {
+		for _, u := range p.Upstreams {
+			key := p.Id + "/" + u.Id
+			nextUpstreams[key] = true
+			if !prevUpstreams[key] {
+				added++
+			}
+		}
+	}
+	for key := range prevUpstreams {
+		if !nextUpstreams[key] {
+			removed++
+		}
+	}
+
+	return fmt.Sprintf("upstreams added=%d removed=%d", added, removed)
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestartRequired(t *testing.T) {
+	base := &Config{}
+	base.Server.HttpHost = "0.0.0.0"
+	base.Server.HttpPort = "8080"
+
+	t.Run("no change", func(t *testing.T) {
+		next := *base
+		if reason := restartRequired(base, &next); reason != "" {
+			t.Fatalf("restartRequired() = %q, want \"\"", reason)
+		}
+	})
+
+	t.Run("http port changed", func(t *testing.T) {
+		next := *base
+		next.Server.HttpPort = "9090"
+		if reason := restartRequired(base, &next); reason == "" {
+			t.Fatalf("restartRequired() = \"\", want a non-empty reason for an httpPort change")
+		}
+	})
+
+	t.Run("unrelated field changed", func(t *testing.T) {
+		next := *base
+		next.LogLevel = "debug"
+		if reason := restartRequired(base, &next); reason != "" {
+			t.Fatalf("restartRequired() = %q, want \"\" for a logLevel-only change", reason)
+		}
+	})
+}
+
+func TestDiffSummary(t *testing.T) {
+	prev := &Config{Projects: []Project{
+		{Id: "p1", Upstreams: []Upstream{{Id: "u1"}, {Id: "u2"}}},
+	}}
+	next := &Config{Projects: []Project{
+		{Id: "p1", Upstreams: []Upstream{{Id: "u1"}, {Id: "u3"}}},
+	}}
+
+	got := diffSummary(prev, next)
+	want := "upstreams added=1 removed=1"
+	if got != want {
+		t.Fatalf("diffSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{Projects: []Project{
+			{Id: "p1", Upstreams: []Upstream{{Id: "u1", Endpoint: "https://node.example"}}},
+		}}
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate() on a valid config = %v, want nil", err)
+	}
+
+	t.Run("duplicate project id", func(t *testing.T) {
+		cfg := &Config{Projects: []Project{{Id: "p1"}, {Id: "p1"}}}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for duplicate project ids")
+		}
+	})
+
+	t.Run("duplicate upstream id", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Upstreams = append(cfg.Projects[0].Upstreams, Upstream{Id: "u1", Endpoint: "https://other.example"})
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for duplicate upstream ids")
+		}
+	})
+
+	t.Run("empty endpoint", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Upstreams[0].Endpoint = ""
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for an empty upstream endpoint")
+		}
+	})
+
+	t.Run("negative maxAttempts", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Retry = &RetryPolicy{MaxAttempts: -1}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for negative maxAttempts")
+		}
+	})
+
+	t.Run("unknown backoff strategy", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Upstreams[0].Retry = &RetryPolicy{Backoff: &RetryBackoff{Strategy: "magic"}}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for an unknown backoff strategy")
+		}
+	})
+
+	t.Run("exponential backoff without multiplier", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Retry = &RetryPolicy{Backoff: &RetryBackoff{Strategy: BackoffExponential, InitialMs: 10, MaxMs: 1000}}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for exponential backoff with multiplier <= 0")
+		}
+	})
+
+	t.Run("invalid method override", func(t *testing.T) {
+		cfg := valid()
+		cfg.Projects[0].Retry = &RetryPolicy{
+			MaxAttempts: 3,
+			Methods: map[string]*RetryPolicy{
+				"eth_getLogs": {MaxAttempts: -5},
+			},
+		}
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Validate() = nil, want an error for an invalid per-method override")
+		}
+	})
+}
+
+func writeConfigFile(t *testing.T, yamlContent string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("failed to write test config file: %v", err)
+	}
+	return path
+}
+
+func TestNewConfigManager_RejectsInvalidConfig(t *testing.T) {
+	path := writeConfigFile(t, `
+projects:
+  - id: p1
+    upstreams:
+      - id: u1
+        endpoint: https://node.example
+      - id: u1
+        endpoint: https://other.example
+`)
+
+	if _, err := NewConfigManager(path); err == nil {
+		t.Fatalf("NewConfigManager() = nil error, want a rejection for duplicate upstream ids on initial load")
+	}
+}
+
+func TestNewConfigManager_AcceptsValidConfig(t *testing.T) {
+	path := writeConfigFile(t, `
+projects:
+  - id: p1
+    upstreams:
+      - id: u1
+        endpoint: https://node.example
+`)
+
+	cm, err := NewConfigManager(path)
+	if err != nil {
+		t.Fatalf("NewConfigManager() unexpected error: %v", err)
+	}
+	if got := cm.Current().Projects[0].Id; got != "p1" {
+		t.Errorf("Current().Projects[0].Id = %q, want \"p1\"", got)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultNamespacesForType(t *testing.T) {
+	tests := []struct {
+		typ  UpstreamType
+		want []string
+	}{
+		{UpstreamTypeFull, []string{"eth", "net", "web3"}},
+		{UpstreamTypeArchive, []string{"eth", "net", "web3", "debug"}},
+		{UpstreamTypeTrace, []string{"eth", "net", "web3", "debug", "trace"}},
+		{UpstreamType("unknown"), nil},
+		{"", nil},
+	}
+
+	for _, tc := range tests {
+		if got := DefaultNamespacesForType(tc.typ); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("DefaultNamespacesForType(%q) = %v, want %v", tc.typ, got, tc.want)
+		}
+	}
+
+	// The returned slice must be a copy so callers can't mutate the built-in table.
+	ns := DefaultNamespacesForType(UpstreamTypeFull)
+	ns[0] = "mutated"
+	if DefaultNamespacesForType(UpstreamTypeFull)[0] == "mutated" {
+		t.Fatalf("DefaultNamespacesForType returned a shared slice, mutation leaked")
+	}
+}
+
+func TestUpstream_Supports(t *testing.T) {
+	tests := []struct {
+		name string
+		u    Upstream
+		m    string
+		want bool
+	}{
+		{"no type, no lists: unrestricted", Upstream{}, "trace_call", true},
+		{"full type allows eth namespace", Upstream{Type: UpstreamTypeFull}, "eth_getBalance", true},
+		{"full type rejects trace namespace", Upstream{Type: UpstreamTypeFull}, "trace_call", false},
+		{"archive type allows debug namespace", Upstream{Type: UpstreamTypeArchive}, "debug_traceTransaction", true},
+		{"archive type rejects trace namespace", Upstream{Type: UpstreamTypeArchive}, "trace_block", false},
+		{"trace type allows trace namespace", Upstream{Type: UpstreamTypeTrace}, "trace_call", true},
+		{"explicit Supported overrides Type defaults", Upstream{Type: UpstreamTypeFull, Supported: []string{"trace_call"}}, "trace_call", true},
+		{"explicit Supported narrows to exact method", Upstream{Supported: []string{"eth_getBalance"}}, "eth_getLogs", false},
+		{"namespace in Supported allows any method in it", Upstream{Supported: []string{"eth"}}, "eth_getLogs", true},
+		{"Excludes wins over Supported", Upstream{Supported: []string{"eth"}, Excludes: []string{"eth_getLogs"}}, "eth_getLogs", false},
+		{"Excludes by namespace wins over Type default", Upstream{Type: UpstreamTypeArchive, Excludes: []string{"debug"}}, "debug_traceTransaction", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.u.Supports(tc.m); got != tc.want {
+				t.Errorf("Supports(%q) = %v, want %v", tc.m, got, tc.want)
+			}
+		})
+	}
+}
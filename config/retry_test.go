@@ -0,0 +1,146 @@
+package config
+
+import "testing"
+
+func TestRetryPolicy_ForMethod_NoOverride(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3, RetryOn: []string{"timeout"}}
+
+	if got := p.ForMethod("eth_call"); got != p {
+		t.Fatalf("ForMethod() with no matching override should return the receiver, got a new value: %+v", got)
+	}
+}
+
+func TestRetryPolicy_ForMethod_PartialOverride(t *testing.T) {
+	p := &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     &RetryBackoff{Strategy: BackoffConstant, InitialMs: 100},
+		RetryOn:     []string{"timeout"},
+		Methods: map[string]*RetryPolicy{
+			"eth_getLogs": {MaxAttempts: 5},
+		},
+	}
+
+	got := p.ForMethod("eth_getLogs")
+
+	if got.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d, want 5 (from override)", got.MaxAttempts)
+	}
+	if got.Backoff != p.Backoff {
+		t.Errorf("Backoff = %+v, want inherited from base policy since override didn't set one", got.Backoff)
+	}
+	if len(got.RetryOn) != 1 || got.RetryOn[0] != "timeout" {
+		t.Errorf("RetryOn = %v, want inherited [\"timeout\"]", got.RetryOn)
+	}
+
+	// The base policy itself must be untouched by computing an override.
+	if p.MaxAttempts != 3 {
+		t.Errorf("base policy MaxAttempts mutated to %d, want unchanged 3", p.MaxAttempts)
+	}
+}
+
+func TestRetryPolicy_ForMethod_FullOverride(t *testing.T) {
+	base := &RetryPolicy{
+		MaxAttempts: 3,
+		RetryOn:     []string{"timeout"},
+		Methods: map[string]*RetryPolicy{
+			"eth_getLogs": {
+				MaxAttempts: 5,
+				Backoff:     &RetryBackoff{Strategy: BackoffExponential, InitialMs: 50, MaxMs: 2000, Multiplier: 2},
+				RetryOn:     []string{"rate limited"},
+			},
+		},
+	}
+
+	got := base.ForMethod("eth_getLogs")
+
+	if got.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d, want 5", got.MaxAttempts)
+	}
+	if got.Backoff == nil || got.Backoff.Strategy != BackoffExponential {
+		t.Errorf("Backoff = %+v, want overridden exponential backoff", got.Backoff)
+	}
+	if len(got.RetryOn) != 1 || got.RetryOn[0] != "rate limited" {
+		t.Errorf("RetryOn = %v, want overridden [\"rate limited\"]", got.RetryOn)
+	}
+}
+
+func TestRetryPolicy_ForMethod_NilPolicy(t *testing.T) {
+	var p *RetryPolicy
+
+	if got := p.ForMethod("eth_call"); got != nil {
+		t.Fatalf("ForMethod() on a nil policy = %+v, want nil", got)
+	}
+}
+
+func TestRetryPolicy_WithOverrides(t *testing.T) {
+	base := &RetryPolicy{MaxAttempts: 3, RetryOn: []string{"timeout"}}
+
+	t.Run("no overrides returns the base policy", func(t *testing.T) {
+		if got := base.WithOverrides(0, nil); got != base {
+			t.Fatalf("WithOverrides(0, nil) = %+v, want the base policy unchanged", got)
+		}
+	})
+
+	t.Run("maxAttempts override", func(t *testing.T) {
+		got := base.WithOverrides(10, nil)
+		if got.MaxAttempts != 10 {
+			t.Errorf("MaxAttempts = %d, want 10", got.MaxAttempts)
+		}
+		if len(got.RetryOn) != 1 || got.RetryOn[0] != "timeout" {
+			t.Errorf("RetryOn = %v, want inherited [\"timeout\"]", got.RetryOn)
+		}
+		if base.MaxAttempts != 3 {
+			t.Errorf("base policy mutated: MaxAttempts = %d, want unchanged 3", base.MaxAttempts)
+		}
+	})
+
+	t.Run("retryOn override", func(t *testing.T) {
+		got := base.WithOverrides(0, []string{"rate limited"})
+		if got.MaxAttempts != 3 {
+			t.Errorf("MaxAttempts = %d, want inherited 3", got.MaxAttempts)
+		}
+		if len(got.RetryOn) != 1 || got.RetryOn[0] != "rate limited" {
+			t.Errorf("RetryOn = %v, want [\"rate limited\"]", got.RetryOn)
+		}
+	})
+
+	t.Run("nil policy with an override synthesizes one", func(t *testing.T) {
+		var nilPolicy *RetryPolicy
+		got := nilPolicy.WithOverrides(5, nil)
+		if got == nil || got.MaxAttempts != 5 {
+			t.Fatalf("WithOverrides on a nil policy = %+v, want a synthesized policy with MaxAttempts=5", got)
+		}
+	})
+
+	t.Run("nil policy with no overrides stays nil", func(t *testing.T) {
+		var nilPolicy *RetryPolicy
+		if got := nilPolicy.WithOverrides(0, nil); got != nil {
+			t.Fatalf("WithOverrides(0, nil) on a nil policy = %+v, want nil", got)
+		}
+	})
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *RetryPolicy
+		attempt int
+		errMsg  string
+		want    bool
+	}{
+		{"nil policy never retries", nil, 1, "timeout", false},
+		{"zero maxAttempts never retries", &RetryPolicy{}, 1, "timeout", false},
+		{"attempt below max with no retryOn filter retries", &RetryPolicy{MaxAttempts: 3}, 1, "anything", true},
+		{"attempt at max does not retry", &RetryPolicy{MaxAttempts: 3}, 3, "anything", false},
+		{"retryOn matches case-insensitively", &RetryPolicy{MaxAttempts: 3, RetryOn: []string{"Rate Limited"}}, 1, "got rate limited by upstream", true},
+		{"retryOn does not match", &RetryPolicy{MaxAttempts: 3, RetryOn: []string{"rate limited"}}, 1, "execution reverted", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.ShouldRetry(tc.attempt, tc.errMsg); got != tc.want {
+				t.Errorf("ShouldRetry(%d, %q) = %v, want %v", tc.attempt, tc.errMsg, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import "testing"
+
+func TestResolveTimeoutMs(t *testing.T) {
+	tests := []struct {
+		name                      string
+		server, project, upstream int
+		want                      int
+	}{
+		{"upstream wins over everything", 1000, 2000, 3000, 3000},
+		{"project wins when no upstream override", 1000, 2000, 0, 2000},
+		{"server is the final fallback", 1000, 0, 0, 1000},
+		{"all unset", 0, 0, 0, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ResolveTimeoutMs(tc.server, tc.project, tc.upstream); got != tc.want {
+				t.Errorf("ResolveTimeoutMs(%d, %d, %d) = %d, want %d", tc.server, tc.project, tc.upstream, got, tc.want)
+			}
+		})
+	}
+}